@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// Admin exposes operational endpoints that aren't meant for public traffic,
+// such as triggering a graceful shutdown without access to the process's
+// signals. That's useful for remote ops tooling and integration tests that
+// can't send the process an OS signal directly.
+type Admin struct {
+	// Token is compared against the request's "Authorization: Bearer
+	// <token>" header. An empty Token rejects every request.
+	Token string
+
+	// Shutdown is invoked once the token check passes.
+	Shutdown func()
+}
+
+// ShutdownHandler handles POST /admin/shutdown.
+func (a *Admin) ShutdownHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if a.Token == "" || !ok || subtle.ConstantTimeCompare([]byte(token), []byte(a.Token)) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	a.Shutdown()
+	w.WriteHeader(http.StatusAccepted)
+	w.Write([]byte("shutdown triggered"))
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdmin_ShutdownHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		authHeader string
+		wantStatus int
+		wantCalled bool
+	}{
+		{
+			name:       "missing token",
+			method:     http.MethodPost,
+			authHeader: "",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "empty bearer token",
+			method:     http.MethodPost,
+			authHeader: "Bearer ",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "wrong token",
+			method:     http.MethodPost,
+			authHeader: "Bearer wrong-token",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "non-POST method",
+			method:     http.MethodGet,
+			authHeader: "Bearer correct-token",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "valid token",
+			method:     http.MethodPost,
+			authHeader: "Bearer correct-token",
+			wantStatus: http.StatusAccepted,
+			wantCalled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			a := &Admin{
+				Token:    "correct-token",
+				Shutdown: func() { called = true },
+			}
+
+			req := httptest.NewRequest(tt.method, "/admin/shutdown", nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			rec := httptest.NewRecorder()
+
+			a.ShutdownHandler(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if called != tt.wantCalled {
+				t.Fatalf("Shutdown called = %v, want %v", called, tt.wantCalled)
+			}
+		})
+	}
+}
+
+func TestAdmin_ShutdownHandler_EmptyTokenRejectsEverything(t *testing.T) {
+	called := false
+	a := &Admin{Shutdown: func() { called = true }}
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/shutdown", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	rec := httptest.NewRecorder()
+
+	a.ShutdownHandler(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	if called {
+		t.Fatal("Shutdown was called with an empty configured token")
+	}
+}
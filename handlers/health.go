@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// Kubernetes readiness probes mark a pod unhealthy after FailThreshold
+// consecutive failed checks spaced PeriodSeconds apart, so kube-proxy /
+// ingress only stops routing traffic to it after that many PeriodSeconds
+// have elapsed. Delta adds a small margin on top so our own drain window is
+// never shorter than what the probe needs to observe us as not-ready.
+const (
+	FailThreshold = 3
+	PeriodSeconds = 1
+	Delta         = 2
+
+	// WaitSeconds is how long Readyz must keep reporting "not ready" before
+	// it's safe to shut the server down.
+	WaitSeconds = FailThreshold*PeriodSeconds + Delta
+)
+
+// Health backs the /healthz (liveness) and /readyz (readiness) endpoints.
+// The zero value reports not ready; NewHealth returns one that is ready.
+// Call SetReady(false) to start draining traffic ahead of a shutdown.
+type Health struct {
+	ready atomic.Bool
+}
+
+// NewHealth returns a Health that reports ready until SetReady(false) is
+// called.
+func NewHealth() *Health {
+	h := &Health{}
+	h.ready.Store(true)
+	return h
+}
+
+// SetReady flips the readiness flag reported by Readyz.
+func (h *Health) SetReady(ready bool) {
+	h.ready.Store(ready)
+}
+
+// Healthz reports liveness: as long as the process can handle this request
+// at all, it answers 200. It never depends on readiness.
+func (h *Health) Healthz(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+// Readyz reports readiness: 200 while the server should keep receiving new
+// traffic, 503 once a shutdown has been triggered and it shouldn't.
+func (h *Health) Readyz(w http.ResponseWriter, r *http.Request) {
+	if !h.ready.Load() {
+		http.Error(w, "not ready", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ready"))
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealth_ReadyzFlipsAfterSetReady(t *testing.T) {
+	h := NewHealth()
+
+	rec := httptest.NewRecorder()
+	h.Readyz(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Readyz before SetReady(false) = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	h.SetReady(false)
+
+	rec = httptest.NewRecorder()
+	h.Readyz(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Readyz after SetReady(false) = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+
+	h.SetReady(true)
+
+	rec = httptest.NewRecorder()
+	h.Readyz(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Readyz after SetReady(true) = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestHealth_HealthzIgnoresReadiness(t *testing.T) {
+	h := NewHealth()
+	h.SetReady(false)
+
+	rec := httptest.NewRecorder()
+	h.Healthz(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Healthz while not ready = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
@@ -14,11 +14,26 @@ type Hello struct{}
 // It's part of the http.Handler interface from the net/http package.
 // Any type that implements this ServeHTTP(ResponseWriter, *Request) method can
 // be used to handle HTTP requests.
+//
+// The body is read synchronously, before ServeHTTP can return: net/http
+// forbids reading r.Body after ServeHTTP completes, so there must be no
+// goroutine still touching it once this method returns. A slow read is
+// already bounded by the server's ReadTimeout, so nothing extra is needed
+// to keep a stalled client from blocking shutdown. Once the body is in
+// hand, r.Context() is checked before writing, so a request that
+// was cancelled mid-read doesn't write a response into a connection the
+// server may already be tearing down.
 func (h *Hello) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	d, err := io.ReadAll(r.Body)
 	if err != nil {
 		http.Error(w, "Oops..", http.StatusBadRequest)
 		return
 	}
+
+	if r.Context().Err() != nil {
+		http.Error(w, "request cancelled", http.StatusServiceUnavailable)
+		return
+	}
+
 	fmt.Fprintf(w, "Hello %s", d)
 }
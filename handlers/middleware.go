@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type contextKey string
+
+const requestIDKey contextKey = "requestID"
+
+// RequestIDFromContext returns the request ID stamped by RequestID, or ""
+// if none is present on ctx.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// RequestID stamps every request with a random ID, stored on its context and
+// echoed back in the X-Request-Id response header, so logging and
+// cancellation can be correlated across a single request's lifetime.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := newRequestID()
+		w.Header().Set("X-Request-Id", id)
+		ctx := context.WithValue(r.Context(), requestIDKey, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// statusWriter captures the status code written to it so Logging can report
+// it; http.ResponseWriter doesn't expose that after the fact.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Logging is a middleware that logs one structured line per request: the
+// request ID stamped by RequestID (if any), method, path, status, duration
+// and remote address.
+func Logging(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(sw, r)
+
+		log.Printf("request_id=%s method=%s path=%s status=%d duration=%s remote_addr=%s",
+			RequestIDFromContext(r.Context()), r.Method, r.URL.Path, sw.status, time.Since(start), r.RemoteAddr)
+	})
+}
+
+// Tracker is a middleware that tracks in-flight requests with a
+// sync.WaitGroup, so a shutdown routine can drain background work a handler
+// spawns and returns from — something http.Server.Shutdown does not track on
+// its own.
+type Tracker struct {
+	wg sync.WaitGroup
+}
+
+// Wrap increments the WaitGroup for the duration of the request.
+func (t *Tracker) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.wg.Add(1)
+		defer t.wg.Done()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Add registers extra in-flight work outside the request lifecycle, such as
+// a background goroutine a handler spawns, so Wait also covers it.
+func (t *Tracker) Add(delta int) {
+	t.wg.Add(delta)
+}
+
+// Done marks one unit of work registered via Add as finished.
+func (t *Tracker) Done() {
+	t.wg.Done()
+}
+
+// Wait blocks until every tracked request and any extra work registered via
+// Add completes, or ctx is done first.
+func (t *Tracker) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
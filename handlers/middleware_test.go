@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTracker_WaitBlocksUntilAddedWorkCompletes(t *testing.T) {
+	tr := &Tracker{}
+	tr.Add(1)
+
+	waitDone := make(chan error, 1)
+	go func() {
+		waitDone <- tr.Wait(context.Background())
+	}()
+
+	select {
+	case err := <-waitDone:
+		t.Fatalf("Wait returned early (err=%v) before Done was called", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	tr.Done()
+
+	select {
+	case err := <-waitDone:
+		if err != nil {
+			t.Fatalf("Wait returned error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not return after Done")
+	}
+}
+
+func TestTracker_WaitRespectsContextCancellation(t *testing.T) {
+	tr := &Tracker{}
+	tr.Add(1)
+	defer tr.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	waitDone := make(chan error, 1)
+	go func() {
+		waitDone <- tr.Wait(ctx)
+	}()
+
+	cancel()
+
+	select {
+	case err := <-waitDone:
+		if err != context.Canceled {
+			t.Fatalf("Wait error = %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not return after ctx cancellation")
+	}
+}
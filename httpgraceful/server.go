@@ -0,0 +1,134 @@
+// Package httpgraceful provides a reusable wrapper around http.Server that
+// drains in-flight requests and shuts down cleanly on a cancelled context,
+// instead of every service hand-rolling its own sigChan/select/Shutdown
+// boilerplate.
+package httpgraceful
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Server wraps an *http.Server and coordinates its graceful shutdown.
+//
+// Server itself does not listen for OS signals: callers derive ctx from
+// signal.NotifyContext (or anything else that cancels on the conditions
+// they care about) and pass it to ListenAndServe, so there is exactly one
+// place in a binary registering for SIGINT/SIGTERM. Trigger is the matching
+// entry point for shutdown requests that don't arrive as an OS signal.
+type Server struct {
+	// Inner is the underlying HTTP server that gets started and shut down.
+	Inner *http.Server
+
+	// ShutdownTimeout bounds how long Shutdown is given to drain in-flight
+	// requests before the remaining connections are forcibly closed.
+	ShutdownTimeout time.Duration
+
+	// WaitBeforeShutdown is an optional grace period observed before
+	// Shutdown is called, giving load balancers time to deregister this
+	// instance and stop routing new traffic to it.
+	WaitBeforeShutdown time.Duration
+
+	// PreShutdown, if set, runs as soon as a shutdown is triggered, before
+	// the WaitBeforeShutdown pause. Services use this to flip a readiness
+	// flag to "not ready" so load balancers stop sending new traffic during
+	// the drain window that follows.
+	PreShutdown func()
+
+	// Drain, if set, runs after Inner.Shutdown returns successfully, bounded
+	// by the same ShutdownTimeout deadline. Shutdown only waits for active
+	// connections to close; Drain is the hook for also waiting on anything
+	// else in flight, such as background goroutines a handler spawned and
+	// returned from, tracked via handlers.Tracker.
+	Drain func(ctx context.Context) error
+
+	// sleep is overridable by tests so WaitBeforeShutdown can be exercised
+	// without actually waiting on the clock.
+	sleep func(time.Duration)
+
+	// shutdownReq lets callers that can't send an OS signal (an admin HTTP
+	// endpoint, an integration test) trigger the same shutdown path. See
+	// Trigger.
+	shutdownReq chan struct{}
+}
+
+// New returns a Server wrapping inner, shutting down within shutdownTimeout
+// once it stops serving.
+func New(inner *http.Server, shutdownTimeout time.Duration) *Server {
+	return &Server{
+		Inner:           inner,
+		ShutdownTimeout: shutdownTimeout,
+		sleep:           time.Sleep,
+		shutdownReq:     make(chan struct{}, 1),
+	}
+}
+
+// Trigger requests a graceful shutdown the same way a cancelled ctx would,
+// for callers that can't send the process an OS signal directly (an admin
+// HTTP endpoint, an orchestration tool, an integration test). It's safe to
+// call more than once and from any goroutine.
+func (s *Server) Trigger() {
+	select {
+	case s.shutdownReq <- struct{}{}:
+	default:
+	}
+}
+
+// ListenAndServe starts the underlying server in a background goroutine and
+// blocks until ctx is cancelled or Trigger is called. Either starts a
+// graceful shutdown: an optional WaitBeforeShutdown pause, then Shutdown
+// bounded by ShutdownTimeout. It returns the first error seen from either
+// serving or shutting down the server.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	if s.sleep == nil {
+		s.sleep = time.Sleep
+	}
+	if s.shutdownReq == nil {
+		s.shutdownReq = make(chan struct{}, 1)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		err := s.Inner.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+			return
+		}
+		serveErr <- nil
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+		log.Println("context cancelled, shutting down server")
+	case <-s.shutdownReq:
+		log.Println("shutdown requested, shutting down server")
+	}
+
+	if s.PreShutdown != nil {
+		s.PreShutdown()
+	}
+
+	if s.WaitBeforeShutdown > 0 {
+		s.sleep(s.WaitBeforeShutdown)
+	}
+
+	tc, cancel := context.WithTimeout(context.Background(), s.ShutdownTimeout)
+	defer cancel()
+
+	if err := s.Inner.Shutdown(tc); err != nil {
+		return err
+	}
+
+	if s.Drain != nil {
+		if err := s.Drain(tc); err != nil {
+			return err
+		}
+	}
+
+	return <-serveErr
+}
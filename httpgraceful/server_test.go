@@ -0,0 +1,109 @@
+package httpgraceful
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder is a small test helper that appends each reported step under a
+// mutex, since PreShutdown/sleep/Drain are called from the goroutine running
+// ListenAndServe rather than the test's own goroutine.
+type recorder struct {
+	mu    sync.Mutex
+	steps []string
+}
+
+func (r *recorder) record(step string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.steps = append(r.steps, step)
+}
+
+func (r *recorder) snapshot() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.steps...)
+}
+
+func newTestServer(rec *recorder) *Server {
+	return &Server{
+		Inner:              &http.Server{Addr: "127.0.0.1:0"},
+		ShutdownTimeout:    time.Second,
+		WaitBeforeShutdown: time.Millisecond,
+		PreShutdown:        func() { rec.record("preshutdown") },
+		Drain: func(ctx context.Context) error {
+			rec.record("drain")
+			return nil
+		},
+		sleep:       func(time.Duration) { rec.record("sleep") },
+		shutdownReq: make(chan struct{}, 1),
+	}
+}
+
+// TestListenAndServe_ShutdownOrder verifies that a triggered shutdown runs
+// PreShutdown, then the WaitBeforeShutdown pause, then Drain, in that order —
+// using the injectable sleep func and Trigger so the test touches neither the
+// real clock nor OS signals. Inner.Shutdown itself is not recorded.
+func TestListenAndServe_ShutdownOrder(t *testing.T) {
+	rec := &recorder{}
+	s := newTestServer(rec)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.ListenAndServe(context.Background())
+	}()
+
+	s.Trigger()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("ListenAndServe returned error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("ListenAndServe did not return after Trigger")
+	}
+
+	got := rec.snapshot()
+	want := []string{"preshutdown", "sleep", "drain"}
+	if len(got) != len(want) {
+		t.Fatalf("steps = %v, want %v", got, want)
+	}
+	for i, step := range want {
+		if got[i] != step {
+			t.Fatalf("steps = %v, want %v", got, want)
+		}
+	}
+}
+
+// TestListenAndServe_ContextCancelled verifies that a cancelled ctx triggers
+// the same shutdown path as Trigger, without any OS signal involved.
+func TestListenAndServe_ContextCancelled(t *testing.T) {
+	rec := &recorder{}
+	s := newTestServer(rec)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.ListenAndServe(ctx)
+	}()
+
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("ListenAndServe returned error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("ListenAndServe did not return after ctx cancellation")
+	}
+
+	if got := rec.snapshot(); len(got) != 3 {
+		t.Fatalf("steps = %v, want 3 steps", got)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,101 +11,74 @@ import (
 	"time"
 
 	"github.com/viveksingh-01/learn-go-microservices/handlers"
+	"github.com/viveksingh-01/learn-go-microservices/httpgraceful"
 )
 
 func main() {
 
+	// ctx is cancelled the moment SIGINT or SIGTERM arrives. Wiring it into
+	// http.Server.BaseContext below means every in-flight request's
+	// r.Context() is cancelled too, not just the server's own accept loop.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// tracker counts in-flight requests (and any background goroutine a
+	// handler registers via Add/Done) so shutdown can drain them, not just
+	// the connections http.Server.Shutdown already tracks.
+	tracker := &handlers.Tracker{}
+
 	// Creates an instance of the Hello handler.
 	hh := &handlers.Hello{}
 
+	// health backs /healthz and /readyz. It starts out ready and flips to
+	// not-ready as soon as a shutdown is triggered, below.
+	health := handlers.NewHealth()
+
 	// Creates a new instance of a ServeMux
 	sm := http.NewServeMux()
 
 	// Registers the Hello handler to handle all incoming requests to the root path (/).
 	sm.Handle("/", hh)
+	sm.HandleFunc("/healthz", health.Healthz)
+	sm.HandleFunc("/readyz", health.Readyz)
+
+	// Request IDs and structured logging wrap every route on sm, including
+	// the health endpoints registered above and the admin endpoint
+	// registered below.
+	handler := handlers.RequestID(handlers.Logging(tracker.Wrap(sm)))
 
 	s := &http.Server{
 		Addr:         ":9090",
-		Handler:      sm,
+		Handler:      handler,
 		IdleTimeout:  120 * time.Second,
 		ReadTimeout:  1 * time.Second,
 		WriteTimeout: 1 * time.Second,
+		BaseContext: func(_ net.Listener) context.Context {
+			return ctx
+		},
 	}
 
-	// Wraps the ListenAndServe function inside a Goroutine (a lightweight, concurrent function execution in Go),
-	// so it doesn't block our graceful shutdown logic below
-	go func() {
-		err := s.ListenAndServe()
-
-		// Add a new check: err != http.ErrServerClosed
-		// When s.Shutdown() is called, ListenAndServe() will return http.ErrServerClosed,
-		// which is an expected error during a graceful shutdown.
-		// We want to log fatal errors only for unexpected issues during server startup.
-		if err != nil && err != http.ErrServerClosed {
-			log.Fatal(err)
-		}
-	}()
-
-	// It's often a good practice to buffer the channel (make(chan os.Signal, 1))
-	// to prevent potential blocking if the signal handler isn't immediately ready to receive.
-	// When a signal is sent to the program, signal.Notify writes the signal to the channel.
-	// If the channel is unbuffered and no goroutine is actively reading from it,
-	// the signal will be dropped. This can cause our program to miss termination signals
-	// like os.Kill or os.Interrupt.
-	sigChan := make(chan os.Signal, 1)
-
-	// This line configure the Go runtime to forward operating system signals
-	// os.Interrupt (usually sent by pressing Ctrl+C) to the sigChan channel.
-	// os.Kill cannot be trapped, so we replaced it with syscall.SIGTERM to handle termination signals properly.
-	// This allows your program to be notified when it's asked to shut down.
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-
-	// We've created a new go func() { ... }() block.
-	// This starts a separate goroutine specifically for handling the received operating system signals.
-	// Inside this goroutine, we still block and wait for a signal on sigChan (sig := <-sigChan)
-	go func() {
-		// This line blocks the execution of the main goroutine until a
-		// signal is received on the sigChan channel.
-		// When a os.Kill or os.Interrupt signal is received,
-		// the value of that signal will be assigned to the sig variable.
-		sig := <-sigChan
-
-		// Once a signal is received, this line logs a message indicating which
-		// signal was received and that the server is going to shut down gracefully.
-		log.Printf("Received signal: %v, going for graceful shutdown.\n", sig)
-
-		// To simulate some cleanup work (closing database connections, etc)
-		time.Sleep(5 * time.Second)
-
-		// This creates a new context.Context with a timeout of 30 seconds.
-		// context.Background() creates an empty root context.
-		// context.WithTimeout() derives a new context from the parent context (context.Background())
-		// that will be automatically canceled after the specified duration (30 seconds).
-		// It returns the new context (tc) and a cancel function.
-		tc, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-
-		// 'defer' schedules the cancel function to be called when the main function exits.
-		// This is important to release resources associated with the context,
-		// even if the shutdown completes successfully before the timeout.
-		defer cancel()
-
-		// This is the crucial part of the graceful shutdown.
-		// s.Shutdown(tc) attempts to gracefully shut down the HTTP server.
-		// It stops accepting new connections and tries to close all idle connections.
-		// It then waits for all active requests to complete (up to the timeout specified in the tc context).
-		// If the timeout is reached before all requests complete,
-		// the server will forcibly close any remaining active connections.
-		if err := s.Shutdown(tc); err != nil {
-			log.Printf("HTTP server shutdown error: %v", err)
-		} else {
-			log.Println("HTTP server gracefully shut down.")
-		}
-	}()
+	// srv wraps s with the graceful-shutdown behavior every service here
+	// needs. On shutdown it flips readiness to false immediately so Readyz
+	// starts failing, waits WaitSeconds for kube-proxy / ingress to notice
+	// and stop routing new traffic, closes the server, and finally drains
+	// via tracker so in-flight requests and their background work finish
+	// too.
+	srv := httpgraceful.New(s, 30*time.Second)
+	srv.PreShutdown = func() { health.SetReady(false) }
+	srv.WaitBeforeShutdown = handlers.WaitSeconds * time.Second
+	srv.Drain = tracker.Wait
+
+	// admin lets orchestration tooling trigger the same graceful shutdown
+	// as a signal would, for environments where sending the process a
+	// signal isn't an option.
+	admin := &handlers.Admin{
+		Token:    os.Getenv("ADMIN_SHUTDOWN_TOKEN"),
+		Shutdown: srv.Trigger,
+	}
+	sm.HandleFunc("/admin/shutdown", admin.ShutdownHandler)
 
-	// We've added an empty select {} at the end of the main function.
-	// This will cause the main goroutine to block indefinitely.
-	// This is important because if the main function exits before the
-	// signal handling goroutine finishes, our program might terminate prematurely,
-	// and the graceful shutdown might not complete.
-	select {}
+	if err := srv.ListenAndServe(ctx); err != nil {
+		log.Fatal(err)
+	}
 }